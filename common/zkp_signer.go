@@ -0,0 +1,15 @@
+package common
+
+import "os"
+
+// ZKP signer backend selection. ZkpPrivateKey (defined alongside the rest of
+// the ZKP login config) remains the key for the default "raw_key" backend;
+// these select and configure the keystore/clef alternatives added so the
+// private key doesn't have to sit unencrypted in env/DB.
+var (
+	ZkpSignerBackend      = os.Getenv("ZKP_SIGNER_BACKEND")
+	ZkpKeystorePath       = os.Getenv("ZKP_KEYSTORE_PATH")
+	ZkpKeystorePassphrase = os.Getenv("ZKP_KEYSTORE_PASSPHRASE")
+	ZkpClefEndpoint       = os.Getenv("ZKP_CLEF_ENDPOINT")
+	ZkpClefAccountAddress = os.Getenv("ZKP_CLEF_ACCOUNT_ADDRESS")
+)
@@ -0,0 +1,15 @@
+package common
+
+import "os"
+
+// ZkpSiweDomain is the domain/URI advertised in the EIP-4361 "Sign-In with
+// Ethereum" style challenge message issued by /api/oauth/zkp/challenge.
+// Falls back to a sane default so local dev doesn't need to set it.
+var ZkpSiweDomain = envOrDefault("ZKP_SIWE_DOMAIN", "localhost")
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
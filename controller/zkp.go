@@ -6,6 +6,7 @@ import (
 	"github.com/QuantumNous/new-api/common"
 	"github.com/QuantumNous/new-api/model"
 	"github.com/QuantumNous/new-api/service"
+	"github.com/QuantumNous/new-api/service/ens"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
@@ -14,6 +15,9 @@ import (
 type ZkpLoginRequest struct {
 	ZkpCode string `json:"zkpCode" binding:"required"`
 	AffCode string `json:"affCode"`
+	// ChainId selects which registered chain to verify the proof against.
+	// Zero (the default) means "use the primary configured chain".
+	ChainId int64 `json:"chainId"`
 }
 
 // abbreviateAddress returns abbreviated wallet address like "0x1234...abcd"
@@ -24,9 +28,66 @@ func abbreviateAddress(address string) string {
 	return address[:6] + "..." + address[len(address)-4:]
 }
 
+// zkpClientFingerprint derives a stable key binding a challenge to the
+// client that requested it, without requiring the client to be logged in.
+func zkpClientFingerprint(c *gin.Context) string {
+	return c.ClientIP() + "|" + c.GetHeader("User-Agent")
+}
+
+type ZkpChallengeRequest struct {
+	// ChainId pins the Chain ID the issued SIWE message advertises. Zero
+	// (the default) uses the primary configured chain.
+	ChainId int64 `json:"chainId"`
+}
+
+// ZkpChallenge issues a short-lived, single-use nonce the caller's ZK
+// circuit must embed as a public input, binding a proof to this one login
+// attempt (see service.IssueZkpChallenge).
+func ZkpChallenge(c *gin.Context) {
+	var req ZkpChallengeRequest
+	// Body is optional; chainId defaults to the primary chain if absent or
+	// unparsable.
+	_ = c.ShouldBindJSON(&req)
+
+	chainId := req.ChainId
+	if chainId == 0 {
+		if chain, ok := service.GetPrimaryZkpChain(); ok {
+			chainId = chain.ChainId
+		}
+	}
+
+	challenge, err := service.IssueZkpChallenge(zkpClientFingerprint(c), chainId)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    challenge,
+	})
+}
+
 func ZkpOAuth(c *gin.Context) {
-	// Check if ZKP private key is configured
-	if common.ZkpPrivateKey == "" {
+	// Check the configured signer backend (raw key, keystore or clef) is
+	// actually usable before doing anything else. Checking common.ZkpPrivateKey
+	// directly would reject every login when the signer backend
+	// (ZKP_SIGNER_BACKEND) is "keystore"/"clef",
+	// since that field is intentionally left blank in those modes.
+	signer, err := service.GetZkpSigner()
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": "ZKP authentication is not configured",
+		})
+		return
+	}
+	if _, err := signer.Address(); err != nil {
+		common.SysLog("ZKP signer not ready: " + err.Error())
 		c.JSON(http.StatusOK, gin.H{
 			"success": false,
 			"message": "ZKP authentication is not configured",
@@ -53,8 +114,30 @@ func ZkpOAuth(c *gin.Context) {
 		return
 	}
 
+	// Consume the server-issued challenge this proof must be bound to. This
+	// must happen before submitting to chain: payload.Input[1] is checked
+	// against the fingerprint's pending nonce and the challenge is
+	// atomically deleted, so a replayed zkpCode is rejected even if the
+	// on-chain hash hasn't been revoked yet.
+	consumed, err := service.ConsumeZkpChallenge(zkpClientFingerprint(c), payload.Input[1])
+	if err != nil {
+		common.SysLog("ZKP challenge lookup failed: " + err.Error())
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": "CHALLENGE_ERROR",
+		})
+		return
+	}
+	if !consumed {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "CHALLENGE_INVALID_OR_EXPIRED",
+		})
+		return
+	}
+
 	// Verify proof and write to chain
-	walletAddress, txHash, err := service.VerifyProof(payload)
+	walletAddress, txHash, err := service.VerifyProof(payload, req.ChainId)
 	if err != nil {
 		common.SysLog("ZKP verification failed: " + err.Error())
 		c.JSON(http.StatusUnauthorized, gin.H{
@@ -65,7 +148,7 @@ func ZkpOAuth(c *gin.Context) {
 	}
 
 	// Check club membership
-	if !service.IsClubMember(walletAddress) {
+	if !service.IsClubMember(walletAddress, req.ChainId) {
 		c.JSON(http.StatusForbidden, gin.H{
 			"success": false,
 			"message": "NOT_CLUB_MEMBER",
@@ -76,6 +159,15 @@ func ZkpOAuth(c *gin.Context) {
 	// Get zkp hash from payload
 	zkpHash := payload.Input[0].String()
 
+	// Resolve a verified ENS/Basenames primary name to use as the display
+	// name, falling back to the abbreviated address when none is set.
+	displayName := abbreviateAddress(walletAddress)
+	if resolution, ok, ensErr := ens.Resolve(walletAddress); ensErr != nil {
+		common.SysLog("ENS resolution failed: " + ensErr.Error())
+	} else if ok {
+		displayName = resolution.Name
+	}
+
 	// Check if user exists
 	user := model.User{
 		WalletAddress: walletAddress,
@@ -101,8 +193,13 @@ func ZkpOAuth(c *gin.Context) {
 			return
 		}
 
-		// Update zkp hash
+		// Update zkp hash. Only refresh the display name if the user hasn't
+		// customized it away from the placeholder abbreviated address, so a
+		// returning user's chosen name isn't clobbered on every login.
 		user.ZkpHash = zkpHash
+		if user.DisplayName == "" || user.DisplayName == abbreviateAddress(walletAddress) {
+			user.DisplayName = displayName
+		}
 		err = user.Update(false)
 		if err != nil {
 			common.SysLog("Failed to update zkp hash: " + err.Error())
@@ -118,7 +215,7 @@ func ZkpOAuth(c *gin.Context) {
 		}
 
 		user.Username = abbreviateAddress(walletAddress)
-		user.DisplayName = abbreviateAddress(walletAddress)
+		user.DisplayName = displayName
 		user.Role = common.RoleCommonUser
 		user.Status = common.UserStatusEnabled
 		user.ZkpHash = zkpHash
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/QuantumNous/new-api/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetZkpChains returns the currently registered ZKP chains. Intended to back
+// the admin settings page that manages multi-chain ZKP login.
+func GetZkpChains(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    service.GetZkpChains(),
+	})
+}
+
+type UpdateZkpChainsRequest struct {
+	Chains  []*service.ChainConfig `json:"chains" binding:"required"`
+	Primary int64                  `json:"primary" binding:"required"`
+}
+
+// GetZkpWatcherHealth reports each chain's event watcher health (last
+// processed block, mode, reconnect count) for the admin dashboard.
+func GetZkpWatcherHealth(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data":    service.GetZkpWatcherHealth(),
+	})
+}
+
+// UpdateZkpChains hot-reloads the ZKP chain registry from the admin settings
+// UI, replacing the set of chains verifyProof/getHashStatus/membership
+// checks are performed against without requiring a restart.
+func UpdateZkpChains(c *gin.Context) {
+	var req UpdateZkpChainsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "INVALID_PAYLOAD",
+		})
+		return
+	}
+
+	if err := service.ReloadZkpChains(req.Chains, req.Primary); err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+	})
+}
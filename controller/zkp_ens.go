@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/QuantumNous/new-api/model"
+	"github.com/QuantumNous/new-api/service/ens"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RefreshUserEns re-runs ENS resolution for the logged-in user on demand,
+// bypassing the user_ens cache. The resolved name becomes the display name
+// only if the current one is empty or the abbreviated-address placeholder.
+func RefreshUserEns(c *gin.Context) {
+	id := c.GetInt("id")
+	user, err := model.GetUserById(id, false)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if user.WalletAddress == "" {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": "NO_WALLET_ADDRESS",
+		})
+		return
+	}
+
+	displayName := abbreviateAddress(user.WalletAddress)
+	resolution, ok, err := ens.Refresh(user.WalletAddress)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+	if ok {
+		displayName = resolution.Name
+	}
+
+	// Only refresh the display name if the user hasn't customized it away
+	// from the placeholder abbreviated address, so a returning user's chosen
+	// name isn't clobbered by a refresh (same guard as ZkpOAuth's
+	// existing-user branch).
+	if user.DisplayName == "" || user.DisplayName == abbreviateAddress(user.WalletAddress) {
+		user.DisplayName = displayName
+	}
+	if err := user.Update(false); err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "",
+		"data": gin.H{
+			"display_name": user.DisplayName,
+		},
+	})
+}
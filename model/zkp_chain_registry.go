@@ -0,0 +1,42 @@
+package model
+
+import "gorm.io/gorm"
+
+// zkpChainRegistryRowId is the single row this table ever holds: the whole
+// admin-configured chain set is stored as one JSON blob rather than one row
+// per chain, since it's always read and replaced as a unit by ReloadZkpChains.
+const zkpChainRegistryRowId = 1
+
+// ZkpChainRegistry persists the admin-configured, hot-reloadable set of ZKP
+// chains, so a process restart doesn't silently revert to the hardcoded
+// default the way an in-memory-only registry would.
+type ZkpChainRegistry struct {
+	ID      int64  `json:"-" gorm:"primaryKey"`
+	Chains  string `json:"chains" gorm:"type:text"`
+	Primary int64  `json:"primary"`
+}
+
+func (ZkpChainRegistry) TableName() string {
+	return "zkp_chain_registry"
+}
+
+// SaveZkpChainRegistry persists chainsJSON/primary as the new source of
+// truth, overwriting whatever was saved before.
+func SaveZkpChainRegistry(chainsJSON string, primary int64) error {
+	row := ZkpChainRegistry{ID: zkpChainRegistryRowId, Chains: chainsJSON, Primary: primary}
+	return DB.Save(&row).Error
+}
+
+// LoadZkpChainRegistry returns the persisted chain set, if ReloadZkpChains
+// has ever been called. ok is false while nothing has been persisted yet.
+func LoadZkpChainRegistry() (chainsJSON string, primary int64, ok bool, err error) {
+	var row ZkpChainRegistry
+	dbErr := DB.Where("id = ?", zkpChainRegistryRowId).First(&row).Error
+	if dbErr == gorm.ErrRecordNotFound {
+		return "", 0, false, nil
+	}
+	if dbErr != nil {
+		return "", 0, false, dbErr
+	}
+	return row.Chains, row.Primary, true, nil
+}
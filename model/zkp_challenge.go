@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ZkpChallenge persists a server-issued, single-use login nonce keyed by the
+// requesting client's fingerprint, so it survives across instances behind a
+// load balancer and can be atomically consumed exactly once.
+type ZkpChallenge struct {
+	Fingerprint string `json:"fingerprint" gorm:"primaryKey;type:varchar(255)"`
+	Nonce       string `json:"nonce" gorm:"type:varchar(64)"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
+
+func (ZkpChallenge) TableName() string {
+	return "zkp_challenge"
+}
+
+// StoreZkpChallenge persists nonce for fingerprint with the given ttl,
+// replacing any previously pending challenge for the same fingerprint (e.g. a
+// client that requests a new challenge before consuming the last one).
+func StoreZkpChallenge(fingerprint string, nonce string, ttl time.Duration) error {
+	challenge := ZkpChallenge{
+		Fingerprint: fingerprint,
+		Nonce:       nonce,
+		ExpiresAt:   time.Now().Add(ttl).Unix(),
+	}
+	return DB.Save(&challenge).Error
+}
+
+// GetZkpChallenge returns fingerprint's pending, unexpired nonce. ok is false
+// if there is none, or it has expired.
+func GetZkpChallenge(fingerprint string) (nonce string, ok bool, err error) {
+	var challenge ZkpChallenge
+	dbErr := DB.Where("fingerprint = ?", fingerprint).First(&challenge).Error
+	if dbErr == gorm.ErrRecordNotFound {
+		return "", false, nil
+	}
+	if dbErr != nil {
+		return "", false, dbErr
+	}
+	if challenge.ExpiresAt < time.Now().Unix() {
+		return "", false, nil
+	}
+	return challenge.Nonce, true, nil
+}
+
+// ConsumeZkpChallenge atomically deletes fingerprint's challenge iff it still
+// matches nonce and hasn't expired, so the same challenge can never be
+// redeemed twice even under concurrent requests.
+func ConsumeZkpChallenge(fingerprint string, nonce string) (bool, error) {
+	result := DB.Where("fingerprint = ? AND nonce = ? AND expires_at >= ?", fingerprint, nonce, time.Now().Unix()).
+		Delete(&ZkpChallenge{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
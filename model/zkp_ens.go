@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// UserEns caches a wallet address's verified ENS/Basenames resolution so the
+// zkp login flow doesn't re-resolve on every login. /api/user/ens/refresh
+// always re-resolves and overwrites the cached entry.
+type UserEns struct {
+	WalletAddress string `json:"wallet_address" gorm:"primaryKey;type:varchar(64)"`
+	Name          string `json:"name" gorm:"type:varchar(255)"`
+	Avatar        string `json:"avatar" gorm:"type:varchar(512)"`
+	ExpiresAt     int64  `json:"expires_at" gorm:"index"`
+}
+
+func (UserEns) TableName() string {
+	return "user_ens"
+}
+
+// GetUserEns returns the cached name/avatar for walletAddress. hit is false
+// on a cache miss or an entry past its TTL, either of which should trigger a
+// live re-resolve.
+func GetUserEns(walletAddress string) (name string, avatar string, hit bool) {
+	var entry UserEns
+	if err := DB.Where("wallet_address = ?", walletAddress).First(&entry).Error; err != nil {
+		return "", "", false
+	}
+	if entry.ExpiresAt < time.Now().Unix() {
+		return "", "", false
+	}
+	return entry.Name, entry.Avatar, true
+}
+
+// UpsertUserEns caches walletAddress's resolution (name/avatar empty for a
+// negative result) for ttl.
+func UpsertUserEns(walletAddress string, name string, avatar string, ttl time.Duration) error {
+	entry := UserEns{
+		WalletAddress: walletAddress,
+		Name:          name,
+		Avatar:        avatar,
+		ExpiresAt:     time.Now().Add(ttl).Unix(),
+	}
+	return DB.Save(&entry).Error
+}
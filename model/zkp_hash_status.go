@@ -0,0 +1,72 @@
+package model
+
+import "gorm.io/gorm"
+
+// ZkpHashStatus persists the latest HashRevoked/HashActivated state the event
+// watcher has observed for one zkp hash on one chain, so IsZkpValid's cache
+// survives a restart instead of starting cold.
+type ZkpHashStatus struct {
+	ChainId  int64  `json:"chain_id" gorm:"primaryKey"`
+	Hash     string `json:"hash" gorm:"primaryKey;type:varchar(128)"`
+	IsActive bool   `json:"is_active"`
+	Deployer string `json:"deployer" gorm:"type:varchar(64)"`
+	Block    uint64 `json:"block"`
+}
+
+func (ZkpHashStatus) TableName() string {
+	return "zkp_hash_status"
+}
+
+// ZkpWatcherState tracks the last block the event watcher has fully
+// processed for one chain, so a restart backfills only the gap instead of
+// re-scanning from genesis.
+type ZkpWatcherState struct {
+	ChainId   int64  `json:"chain_id" gorm:"primaryKey"`
+	LastBlock uint64 `json:"last_block"`
+}
+
+func (ZkpWatcherState) TableName() string {
+	return "zkp_watcher_state"
+}
+
+// GetZkpLastProcessedBlock returns the last block the watcher for chainId
+// has processed, or 0 if it has never run before.
+func GetZkpLastProcessedBlock(chainId int64) (uint64, error) {
+	var state ZkpWatcherState
+	err := DB.Where("chain_id = ?", chainId).First(&state).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return state.LastBlock, nil
+}
+
+// SetZkpLastProcessedBlock persists the last block chainId's watcher has
+// processed.
+func SetZkpLastProcessedBlock(chainId int64, block uint64) error {
+	state := ZkpWatcherState{ChainId: chainId, LastBlock: block}
+	return DB.Save(&state).Error
+}
+
+// UpsertZkpHashStatus persists the latest known status of hash on chainId.
+func UpsertZkpHashStatus(chainId int64, hash string, isActive bool, deployer string, block uint64) error {
+	status := ZkpHashStatus{ChainId: chainId, Hash: hash, IsActive: isActive, Deployer: deployer, Block: block}
+	return DB.Save(&status).Error
+}
+
+// ListZkpHashStatuses returns every persisted hash status for chainId, keyed
+// by hash, so the watcher can warm its in-memory cache on startup.
+func ListZkpHashStatuses(chainId int64) (map[string]ZkpHashStatus, error) {
+	var rows []ZkpHashStatus
+	if err := DB.Where("chain_id = ?", chainId).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]ZkpHashStatus, len(rows))
+	for _, row := range rows {
+		statuses[row.Hash] = row
+	}
+	return statuses, nil
+}
@@ -0,0 +1,15 @@
+package model
+
+// MigrateZkpTables creates/updates every table the zkp login feature needs.
+// It must be called once during startup, after the database connection is
+// established — package init() runs before main assigns DB, so an
+// AutoMigrate there would silently never run.
+func MigrateZkpTables() error {
+	return DB.AutoMigrate(
+		&ZkpChainRegistry{},
+		&UserEns{},
+		&ZkpHashStatus{},
+		&ZkpWatcherState{},
+		&ZkpChallenge{},
+	)
+}
@@ -0,0 +1,268 @@
+// Package ens resolves a wallet address's verified primary ENS (or
+// Basenames) name for use as a ZKP-login user's display name. Resolution
+// follows the standard ENS reverse-record flow and always performs the
+// mandatory forward-resolve check, so a reverse record alone can never be
+// used to spoof a display name for an address its owner doesn't control.
+package ens
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/QuantumNous/new-api/common"
+	"github.com/QuantumNous/new-api/model"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Registry describes one ENS-compatible deployment to check for a primary
+// name, in priority order.
+type Registry struct {
+	Name            string
+	RpcUrl          string
+	RegistryAddress string
+}
+
+// DefaultRegistries is walked in order until a verified primary name is
+// found. Ethereum mainnet ENS is authoritative; Base's Basenames registry is
+// also checked since ZKP logins are predominantly Base wallets.
+var DefaultRegistries = []Registry{
+	{
+		Name:            "mainnet",
+		RpcUrl:          "https://cloudflare-eth.com",
+		RegistryAddress: "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e",
+	},
+	{
+		Name:            "base",
+		RpcUrl:          "https://mainnet.base.org",
+		RegistryAddress: "0xB94704422c2a1E396835A571837Aa5AE53285a95",
+	},
+}
+
+const cacheTTL = 24 * time.Hour
+
+// clientPool caches one dialed ethclient.Client per registry, mirroring the
+// keyed connection pool service/zkp_client_pool.go established, so a
+// cache-miss login doesn't open a fresh RPC connection on every call.
+var clientPool = struct {
+	mu      sync.Mutex
+	clients map[string]*ethclient.Client
+}{clients: make(map[string]*ethclient.Client)}
+
+func getClient(registry Registry) (*ethclient.Client, error) {
+	clientPool.mu.Lock()
+	defer clientPool.mu.Unlock()
+
+	if client, ok := clientPool.clients[registry.Name]; ok {
+		return client, nil
+	}
+
+	client, err := ethclient.Dial(registry.RpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", registry.Name, err)
+	}
+	clientPool.clients[registry.Name] = client
+	return client, nil
+}
+
+const registryABI = `[{"inputs":[{"type":"bytes32","name":"node"}],"name":"resolver","outputs":[{"type":"address","name":""}],"stateMutability":"view","type":"function"}]`
+
+const resolverABI = `[
+	{"inputs":[{"type":"bytes32","name":"node"}],"name":"name","outputs":[{"type":"string","name":""}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"type":"bytes32","name":"node"}],"name":"addr","outputs":[{"type":"address","name":""}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"type":"bytes32","name":"node"},{"type":"string","name":"key"}],"name":"text","outputs":[{"type":"string","name":""}],"stateMutability":"view","type":"function"}
+]`
+
+// Resolution is a verified primary name and its optional avatar text record.
+type Resolution struct {
+	Name   string
+	Avatar string
+}
+
+// Resolve returns walletAddress's verified primary name, preferring the
+// user_ens cache over a live lookup. ok is false if no verified name exists.
+func Resolve(walletAddress string) (*Resolution, bool, error) {
+	if name, avatar, hit := model.GetUserEns(walletAddress); hit {
+		if name == "" {
+			return nil, false, nil
+		}
+		return &Resolution{Name: name, Avatar: avatar}, true, nil
+	}
+	return Refresh(walletAddress)
+}
+
+// Refresh always performs a live lookup, bypassing the cache, and persists
+// whatever it finds (including a negative result) back to the cache with a
+// fresh TTL. Use this for the on-demand /api/user/ens/refresh endpoint.
+func Refresh(walletAddress string) (*Resolution, bool, error) {
+	resolution, found, err := resolveLive(walletAddress)
+	if err != nil {
+		return nil, false, err
+	}
+
+	name, avatar := "", ""
+	if found {
+		name, avatar = resolution.Name, resolution.Avatar
+	}
+	if cacheErr := model.UpsertUserEns(walletAddress, name, avatar, cacheTTL); cacheErr != nil {
+		common.SysLog(fmt.Sprintf("ens: failed to cache resolution for %s: %v", walletAddress, cacheErr))
+	}
+
+	if !found {
+		return nil, false, nil
+	}
+	return resolution, true, nil
+}
+
+// resolveLive walks DefaultRegistries performing a reverse lookup followed by
+// a mandatory forward-resolve check, returning the first verified match.
+func resolveLive(walletAddress string) (*Resolution, bool, error) {
+	var lastErr error
+	for _, registry := range DefaultRegistries {
+		res, ok, err := resolveWithRegistry(registry, walletAddress)
+		if err != nil {
+			lastErr = err
+			common.SysLog(fmt.Sprintf("ens: %s lookup failed for %s: %v", registry.Name, walletAddress, err))
+			continue
+		}
+		if ok {
+			return res, true, nil
+		}
+	}
+	return nil, false, lastErr
+}
+
+func resolveWithRegistry(registry Registry, walletAddress string) (*Resolution, bool, error) {
+	client, err := getClient(registry)
+	if err != nil {
+		return nil, false, err
+	}
+
+	regABI, err := abi.JSON(strings.NewReader(registryABI))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse registry ABI: %w", err)
+	}
+	resABI, err := abi.JSON(strings.NewReader(resolverABI))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse resolver ABI: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	registryAddr := ethcommon.HexToAddress(registry.RegistryAddress)
+	reverseNode := namehash(strings.ToLower(strings.TrimPrefix(walletAddress, "0x")) + ".addr.reverse")
+
+	resolverAddr, err := callAddress(ctx, client, regABI, registryAddr, "resolver", reverseNode)
+	if err != nil {
+		return nil, false, err
+	}
+	if resolverAddr == (ethcommon.Address{}) {
+		return nil, false, nil // no reverse record set on this registry
+	}
+
+	name, err := callString(ctx, client, resABI, resolverAddr, "name", reverseNode)
+	if err != nil {
+		return nil, false, err
+	}
+	if name == "" {
+		return nil, false, nil
+	}
+
+	// Mandatory forward-resolve check: the claimed name must resolve back
+	// to walletAddress, otherwise anyone could set a reverse record
+	// pointing at an arbitrary name they don't own.
+	forwardNode := namehash(name)
+	forwardResolverAddr, err := callAddress(ctx, client, regABI, registryAddr, "resolver", forwardNode)
+	if err != nil {
+		return nil, false, err
+	}
+	if forwardResolverAddr == (ethcommon.Address{}) {
+		return nil, false, nil
+	}
+
+	resolvedAddr, err := callAddress(ctx, client, resABI, forwardResolverAddr, "addr", forwardNode)
+	if err != nil {
+		return nil, false, err
+	}
+	if !strings.EqualFold(resolvedAddr.Hex(), walletAddress) {
+		return nil, false, nil
+	}
+
+	avatar, err := callString(ctx, client, resABI, forwardResolverAddr, "text", forwardNode, "avatar")
+	if err != nil {
+		// Avatar is best-effort; a missing text record shouldn't
+		// invalidate an otherwise-verified name.
+		common.SysLog(fmt.Sprintf("ens: avatar lookup failed for %s: %v", name, err))
+		avatar = ""
+	}
+
+	return &Resolution{Name: name, Avatar: avatar}, true, nil
+}
+
+func callAddress(ctx context.Context, client *ethclient.Client, contractABI abi.ABI, to ethcommon.Address, method string, args ...interface{}) (ethcommon.Address, error) {
+	out, err := call(ctx, client, contractABI, to, method, args...)
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	addr, ok := out[0].(ethcommon.Address)
+	if !ok {
+		return ethcommon.Address{}, fmt.Errorf("%s: unexpected return type", method)
+	}
+	return addr, nil
+}
+
+func callString(ctx context.Context, client *ethclient.Client, contractABI abi.ABI, to ethcommon.Address, method string, args ...interface{}) (string, error) {
+	out, err := call(ctx, client, contractABI, to, method, args...)
+	if err != nil {
+		return "", err
+	}
+	s, ok := out[0].(string)
+	if !ok {
+		return "", fmt.Errorf("%s: unexpected return type", method)
+	}
+	return s, nil
+}
+
+func call(ctx context.Context, client *ethclient.Client, contractABI abi.ABI, to ethcommon.Address, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s call failed: %w", method, err)
+	}
+
+	out, err := contractABI.Unpack(method, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s result: %w", method, err)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s returned no outputs", method)
+	}
+	return out, nil
+}
+
+// namehash implements the EIP-137 namehash algorithm: the recursive
+// keccak256 hash used to derive an ENS node from a dotted name.
+func namehash(name string) [32]byte {
+	var node [32]byte
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		copy(node[:], crypto.Keccak256(append(node[:], labelHash...)))
+	}
+	return node
+}
@@ -14,11 +14,12 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// Constants - hardcoded as per requirements
+// Default chain constants. These seed the primary entry of the zkp chain
+// registry (see zkp_config.go); admins can register additional chains or
+// repoint these at runtime via ReloadZkpChains.
 const (
 	ZkpContractAddress     = "0x7587CA385f1e10c411638003dA0f1bd3C99b919e"
 	MembershipQueryAddress = "0x2A152405afB201258D66919570BbD4625455a65f"
@@ -27,14 +28,16 @@ const (
 	AllowedClubName        = "ai"
 )
 
-// ABI definitions
+// ABI definitions. "input" is [zkpHash, nonceCommitment]: the circuit now
+// takes the server-issued challenge nonce as a second public input so a
+// proof is bound to one login attempt (see zkp_challenge.go).
 const zkpContractABI = `[
 	{
 		"inputs": [
 			{"type": "uint256[2]", "name": "a"},
 			{"type": "uint256[2][2]", "name": "b"},
 			{"type": "uint256[2]", "name": "c"},
-			{"type": "uint256[1]", "name": "input"}
+			{"type": "uint256[2]", "name": "input"}
 		],
 		"name": "verifyProof",
 		"outputs": [
@@ -54,6 +57,24 @@ const zkpContractABI = `[
 		],
 		"stateMutability": "view",
 		"type": "function"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": false, "type": "bytes32", "name": "hash"},
+			{"indexed": false, "type": "address", "name": "deployer"}
+		],
+		"name": "HashRevoked",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": false, "type": "bytes32", "name": "hash"},
+			{"indexed": false, "type": "address", "name": "deployer"}
+		],
+		"name": "HashActivated",
+		"type": "event"
 	}
 ]`
 
@@ -80,7 +101,10 @@ type ZkpPayload struct {
 	A     [2]*big.Int
 	B     [2][2]*big.Int
 	C     [2]*big.Int
-	Input [1]*big.Int
+	// Input is [zkpHash, nonceCommitment]. nonceCommitment must match the
+	// NonceCommitment of a still-pending, server-issued challenge (see
+	// ConsumeZkpChallenge), binding this proof to one login attempt.
+	Input [2]*big.Int
 }
 
 type ZkpStatus struct {
@@ -97,22 +121,31 @@ type MembershipStatus struct {
 	IsCrossChain bool
 }
 
-// Client singleton
+// ABI cache. Ethereum client connections are no longer a single global but a
+// keyed pool (see zkp_client_pool.go) so each configured chain gets its own
+// lazily-dialed, health-checked connection.
 var (
-	ethClient     *ethclient.Client
-	ethClientOnce sync.Once
-	ethClientErr  error
-
 	zkpABI        abi.ABI
 	membershipABI abi.ABI
 	abiOnce       sync.Once
 )
 
-func getEthClient() (*ethclient.Client, error) {
-	ethClientOnce.Do(func() {
-		ethClient, ethClientErr = ethclient.Dial(ZkpRpcUrl)
-	})
-	return ethClient, ethClientErr
+// resolveChain returns the chain to operate on: the explicitly requested
+// chainId if one was given (non-zero), otherwise the configured primary
+// chain.
+func resolveChain(chainId int64) (*ChainConfig, error) {
+	if chainId != 0 {
+		chain, ok := GetZkpChain(chainId)
+		if !ok {
+			return nil, fmt.Errorf("zkp: chain %d is not configured", chainId)
+		}
+		return chain, nil
+	}
+	chain, ok := GetPrimaryZkpChain()
+	if !ok {
+		return nil, errors.New("zkp: no chain configured")
+	}
+	return chain, nil
 }
 
 func getABIs() (abi.ABI, abi.ABI, error) {
@@ -127,7 +160,9 @@ func getABIs() (abi.ABI, abi.ABI, error) {
 	return zkpABI, membershipABI, err
 }
 
-// ParseZkpCode parses comma-separated zkpCode into ZkpPayload
+// ParseZkpCode parses comma-separated zkpCode into ZkpPayload. The last two
+// values are the public inputs: the zkpHash commitment followed by the
+// SIWE-style challenge nonce commitment (see zkp_challenge.go).
 func ParseZkpCode(code string) (*ZkpPayload, error) {
 	// Remove zero-width spaces and other invisible characters
 	cleanStr := strings.ReplaceAll(code, "\u200B", "")
@@ -137,8 +172,8 @@ func ParseZkpCode(code string) (*ZkpPayload, error) {
 	cleanStr = strings.TrimSpace(cleanStr)
 
 	parts := strings.Split(cleanStr, ",")
-	if len(parts) != 9 {
-		return nil, errors.New("invalid zkpCode: expected 9 comma-separated values")
+	if len(parts) != 10 {
+		return nil, errors.New("invalid zkpCode: expected 10 comma-separated values")
 	}
 
 	// Trim each part
@@ -147,7 +182,7 @@ func ParseZkpCode(code string) (*ZkpPayload, error) {
 	}
 
 	// Parse all values
-	values := make([]*big.Int, 9)
+	values := make([]*big.Int, 10)
 	for i, part := range parts {
 		// Use base 0 to auto-detect format: 0x for hex, plain numbers for decimal
 		val, ok := new(big.Int).SetString(part, 0)
@@ -161,17 +196,20 @@ func ParseZkpCode(code string) (*ZkpPayload, error) {
 		A:     [2]*big.Int{values[0], values[1]},
 		B:     [2][2]*big.Int{{values[2], values[3]}, {values[4], values[5]}},
 		C:     [2]*big.Int{values[6], values[7]},
-		Input: [1]*big.Int{values[8]},
+		Input: [2]*big.Int{values[8], values[9]},
 	}, nil
 }
 
-// VerifyProof calls the contract to verify the proof and writes to chain
-func VerifyProof(payload *ZkpPayload) (walletAddress string, txHash string, err error) {
-	if common.ZkpPrivateKey == "" {
-		return "", "", errors.New("ZKP_PRIVATE_KEY not configured")
+// VerifyProof calls the contract to verify the proof and writes to chain.
+// chainId selects which configured chain to submit the proof to; pass 0 to
+// use the primary chain.
+func VerifyProof(payload *ZkpPayload, chainId int64) (walletAddress string, txHash string, err error) {
+	chain, err := resolveChain(chainId)
+	if err != nil {
+		return "", "", err
 	}
 
-	client, err := getEthClient()
+	client, err := ethClientPool.Get(chain)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to connect to ethereum client: %w", err)
 	}
@@ -181,46 +219,46 @@ func VerifyProof(payload *ZkpPayload) (walletAddress string, txHash string, err
 		return "", "", fmt.Errorf("failed to parse ABI: %w", err)
 	}
 
-	// Parse private key
-	privateKeyStr := common.ZkpPrivateKey
-	if strings.HasPrefix(privateKeyStr, "0x") {
-		privateKeyStr = privateKeyStr[2:]
+	signer, err := GetZkpSigner()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to init zkp signer: %w", err)
 	}
-	privateKey, err := crypto.HexToECDSA(privateKeyStr)
+
+	fromAddress, err := signer.Address()
 	if err != nil {
-		return "", "", fmt.Errorf("invalid private key: %w", err)
+		return "", "", fmt.Errorf("failed to resolve signer address: %w", err)
 	}
 
-	// Get chain ID
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Allow enough time for a few stuck-transaction fee bumps, not just the
+	// initial submission.
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
 	defer cancel()
 
-	chainID := big.NewInt(ZkpChainId)
+	chainID := big.NewInt(chain.ChainId)
 
 	// Create auth
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	auth, err := signer.TransactOpts(chainID)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to create transactor: %w", err)
 	}
 
-	// Get nonce
-	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
-	nonce, err := client.PendingNonceAt(ctx, fromAddress)
+	// EIP-1559 dynamic fee: tip from the node's own suggestion, fee cap from
+	// the pending base fee. Leaving GasPrice nil makes go-ethereum emit a
+	// DynamicFeeTx instead of a legacy one.
+	tipCap, err := client.SuggestGasTipCap(ctx)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get nonce: %w", err)
+		return "", "", fmt.Errorf("failed to get gas tip cap: %w", err)
 	}
-	auth.Nonce = big.NewInt(int64(nonce))
-
-	// Get gas price
-	gasPrice, err := client.SuggestGasPrice(ctx)
+	header, err := client.HeaderByNumber(ctx, nil)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get gas price: %w", err)
+		return "", "", fmt.Errorf("failed to get latest header: %w", err)
 	}
-	auth.GasPrice = gasPrice
+	auth.GasTipCap = tipCap
+	auth.GasFeeCap = new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), tipCap)
 	auth.GasLimit = uint64(300000) // Set a reasonable gas limit
 
 	// Pack the call data
-	contractAddr := ethcommon.HexToAddress(ZkpContractAddress)
+	contractAddr := ethcommon.HexToAddress(chain.ZkpContractAddress)
 	callData, err := zkpABI.Pack("verifyProof", payload.A, payload.B, payload.C, payload.Input)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to pack call data: %w", err)
@@ -264,19 +302,42 @@ func VerifyProof(payload *ZkpPayload) (walletAddress string, txHash string, err
 
 	walletAddress = hashDeployer.Hex()
 
-	// Now send the actual transaction
-	tx, err := bind.NewBoundContract(contractAddr, zkpABI, client, client, client).Transact(auth, "verifyProof", payload.A, payload.B, payload.C, payload.Input)
+	// Only now, with the proof already confirmed valid by simulation, reserve
+	// a nonce: every failure above this point (ABI pack, RPC, simulation,
+	// invalid proof) returns before allocating one, so a rejected proof can
+	// never leak a reserved nonce that's never broadcast.
+	nonce, err := zkpNonceManager.Next(ctx, client, chain.ChainId, fromAddress)
 	if err != nil {
+		return walletAddress, "", err
+	}
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+
+	// Now send the actual transaction, bumping fees and resubmitting if it
+	// gets stuck, and waiting for it to be mined.
+	boundContract := bind.NewBoundContract(contractAddr, zkpABI, client, client, client)
+	receipt, err := sendAndWaitMined(ctx, client, boundContract, auth, "verifyProof", payload.A, payload.B, payload.C, payload.Input)
+	if err != nil {
+		zkpNonceManager.Resync(chain.ChainId, fromAddress)
 		return walletAddress, "", fmt.Errorf("failed to send transaction: %w", err)
 	}
 
-	txHash = tx.Hash().Hex()
+	txHash = receipt.TxHash.Hex()
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return walletAddress, txHash, fmt.Errorf("verifyProof transaction reverted")
+	}
+
 	return walletAddress, txHash, nil
 }
 
-// GetHashStatus queries the zkp hash status from the contract
-func GetHashStatus(zkpHash string) (*ZkpStatus, error) {
-	client, err := getEthClient()
+// GetHashStatus queries the zkp hash status from the contract on chainId (0
+// for the primary chain).
+func GetHashStatus(zkpHash string, chainId int64) (*ZkpStatus, error) {
+	chain, err := resolveChain(chainId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ethClientPool.Get(chain)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to ethereum client: %w", err)
 	}
@@ -298,7 +359,7 @@ func GetHashStatus(zkpHash string) (*ZkpStatus, error) {
 	var hash32 [32]byte
 	copy(hash32[:], hashBytes)
 
-	contractAddr := ethcommon.HexToAddress(ZkpContractAddress)
+	contractAddr := ethcommon.HexToAddress(chain.ZkpContractAddress)
 	callData, err := zkpABI.Pack("getHashStatus", hash32)
 	if err != nil {
 		return nil, fmt.Errorf("failed to pack call data: %w", err)
@@ -337,13 +398,30 @@ func GetHashStatus(zkpHash string) (*ZkpStatus, error) {
 	}, nil
 }
 
-// IsZkpValid checks if the zkp hash is still valid (not revoked)
-func IsZkpValid(zkpHash string) bool {
+// IsZkpValid checks if the zkp hash is still valid (not revoked) on chainId
+// (0 for the primary chain).
+func IsZkpValid(zkpHash string, chainId int64) bool {
 	if zkpHash == "" {
 		return true // Non-ZKP user, considered valid
 	}
 
-	status, err := GetHashStatus(zkpHash)
+	chain, err := resolveChain(chainId)
+	if err != nil {
+		common.SysLog(fmt.Sprintf("Error resolving zkp chain: %v", err))
+		return false
+	}
+
+	// The event watcher keeps an up-to-date local cache of every hash it has
+	// seen revoked/activated, so the common case is an O(1) lookup with no
+	// RPC round-trip. Only fall back to a live contract read for hashes the
+	// watcher hasn't indexed yet (e.g. right after it started).
+	if watcher := getZkpEventWatcher(chain.ChainId); watcher != nil {
+		if status, ok := watcher.Lookup(zkpHash); ok {
+			return status.Exists && status.IsActive
+		}
+	}
+
+	status, err := GetHashStatus(zkpHash, chain.ChainId)
 	if err != nil {
 		common.SysLog(fmt.Sprintf("Error checking ZKP status: %v", err))
 		return false // Strict mode: deny on error
@@ -353,8 +431,14 @@ func IsZkpValid(zkpHash string) bool {
 }
 
 // CheckClubMembership checks if the user is a member of the specified club
-func CheckClubMembership(walletAddress string, clubName string) (*MembershipStatus, error) {
-	client, err := getEthClient()
+// on chainId (0 for the primary chain).
+func CheckClubMembership(walletAddress string, clubName string, chainId int64) (*MembershipStatus, error) {
+	chain, err := resolveChain(chainId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ethClientPool.Get(chain)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to ethereum client: %w", err)
 	}
@@ -365,7 +449,7 @@ func CheckClubMembership(walletAddress string, clubName string) (*MembershipStat
 	}
 
 	memberAddr := ethcommon.HexToAddress(walletAddress)
-	contractAddr := ethcommon.HexToAddress(MembershipQueryAddress)
+	contractAddr := ethcommon.HexToAddress(chain.MembershipQueryAddress)
 
 	callData, err := memberABI.Pack("checkDetailedMembership", memberAddr, clubName)
 	if err != nil {
@@ -408,17 +492,57 @@ func CheckClubMembership(walletAddress string, clubName string) (*MembershipStat
 	}, nil
 }
 
-// IsClubMember checks if the user is a member of the allowed club
-func IsClubMember(walletAddress string) bool {
+// IsClubMember checks if the user is a member of the allowed club on
+// chainId (0 for the primary chain). If the membership comes back
+// cross-chain, every other configured chain is also queried and OR-ed in,
+// since a cross-chain membership may only be visible from a different
+// chain's registry.
+func IsClubMember(walletAddress string, chainId int64) bool {
 	if walletAddress == "" {
 		return true // Non-ZKP user, skip check
 	}
 
-	status, err := CheckClubMembership(walletAddress, AllowedClubName)
+	chain, err := resolveChain(chainId)
+	if err != nil {
+		common.SysLog(fmt.Sprintf("Error resolving zkp chain: %v", err))
+		return false
+	}
+
+	status, err := CheckClubMembership(walletAddress, chain.AllowedClubName, chain.ChainId)
 	if err != nil {
 		common.SysLog(fmt.Sprintf("Error checking club membership: %v", err))
 		return false // Strict mode: deny on error
 	}
 
-	return status.IsMember
+	if status.IsMember {
+		return true
+	}
+
+	if status.IsCrossChain {
+		return checkOtherChainsForMembership(walletAddress, chain.ChainId)
+	}
+
+	return false
+}
+
+// checkOtherChainsForMembership queries every configured chain other than
+// skipChainId and reports membership if any of them consider the wallet a
+// member. Individual chain failures are logged and otherwise ignored so one
+// misconfigured or unreachable chain doesn't block membership established
+// elsewhere.
+func checkOtherChainsForMembership(walletAddress string, skipChainId int64) bool {
+	for _, chain := range GetZkpChains() {
+		if chain.ChainId == skipChainId {
+			continue
+		}
+		status, err := CheckClubMembership(walletAddress, chain.AllowedClubName, chain.ChainId)
+		if err != nil {
+			common.SysLog(fmt.Sprintf("Error checking cross-chain membership on chain %d: %v", chain.ChainId, err))
+			continue
+		}
+		if status.IsMember {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/QuantumNous/new-api/common"
+	"github.com/QuantumNous/new-api/model"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// zkpChallengeTTL bounds how long a server-issued nonce stays redeemable.
+const zkpChallengeTTL = 5 * time.Minute
+
+// ZkpChallenge is a server-issued, short-lived nonce a client's ZK circuit
+// must commit to as a public input, binding one proof to one login attempt
+// (EIP-4361 "Sign-In with Ethereum" style) so a leaked zkpCode can't be
+// replayed, independent of the on-chain revocation cache.
+type ZkpChallenge struct {
+	Nonce          string    `json:"nonce"`
+	Message        string    `json:"message"`
+	IssuedAt       time.Time `json:"issuedAt"`
+	ExpirationTime time.Time `json:"expirationTime"`
+}
+
+// IssueZkpChallenge generates a random 128-bit nonce for fingerprint (a
+// stable key derived from the requesting client), stores it with a TTL, and
+// returns the canonical SIWE message describing the challenge.
+func IssueZkpChallenge(fingerprint string, chainId int64) (*ZkpChallenge, error) {
+	nonceBytes := make([]byte, 16) // 128 bits
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate challenge nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(nonceBytes)
+
+	issuedAt := time.Now()
+	expirationTime := issuedAt.Add(zkpChallengeTTL)
+
+	if err := model.StoreZkpChallenge(fingerprint, nonce, zkpChallengeTTL); err != nil {
+		return nil, fmt.Errorf("failed to store challenge: %w", err)
+	}
+
+	message := fmt.Sprintf(
+		"%s wants you to sign in with your Ethereum account.\n\n"+
+			"URI: %s\nVersion: 1\nChain ID: %d\nNonce: %s\nIssued At: %s\nExpiration Time: %s",
+		common.ZkpSiweDomain, common.ZkpSiweDomain, chainId, nonce,
+		issuedAt.Format(time.RFC3339), expirationTime.Format(time.RFC3339),
+	)
+
+	return &ZkpChallenge{
+		Nonce:          nonce,
+		Message:        message,
+		IssuedAt:       issuedAt,
+		ExpirationTime: expirationTime,
+	}, nil
+}
+
+// NonceCommitment hashes nonce the way the ZK circuit is expected to, so it
+// can be compared against a proof's second public input.
+func NonceCommitment(nonce string) *big.Int {
+	return new(big.Int).SetBytes(crypto.Keccak256([]byte(nonce)))
+}
+
+// ConsumeZkpChallenge checks whether input matches fingerprint's pending
+// challenge nonce and, if so, atomically deletes it so the same challenge
+// can never be consumed twice. A false result (with a nil error) means there
+// was no pending challenge, it expired, or input didn't match.
+func ConsumeZkpChallenge(fingerprint string, input *big.Int) (bool, error) {
+	nonce, ok, err := model.GetZkpChallenge(fingerprint)
+	if err != nil {
+		return false, fmt.Errorf("failed to load challenge: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+
+	if input == nil || NonceCommitment(nonce).Cmp(input) != 0 {
+		return false, nil
+	}
+
+	return model.ConsumeZkpChallenge(fingerprint, nonce)
+}
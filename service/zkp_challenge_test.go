@@ -0,0 +1,19 @@
+package service
+
+import "testing"
+
+func TestNonceCommitmentIsDeterministic(t *testing.T) {
+	a := NonceCommitment("abc123")
+	b := NonceCommitment("abc123")
+	if a.Cmp(b) != 0 {
+		t.Fatal("NonceCommitment should be deterministic for the same nonce")
+	}
+}
+
+func TestNonceCommitmentDiffersPerNonce(t *testing.T) {
+	a := NonceCommitment("abc123")
+	b := NonceCommitment("def456")
+	if a.Cmp(b) == 0 {
+		t.Fatal("NonceCommitment should differ for different nonces")
+	}
+}
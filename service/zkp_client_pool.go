@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/QuantumNous/new-api/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ethClientPool lazily dials and health-checks one ethclient.Client per
+// configured chain. It replaces the old single global ethClient/ethClientOnce
+// so that ZkpOAuth can verify proofs against whichever chain a request
+// targets, and so chains can be hot-added/removed via ReloadZkpChains without
+// leaking stale connections.
+var ethClientPool = newZkpClientPool()
+
+const (
+	zkpDialMaxAttempts = 3
+	zkpDialRetryDelay  = 500 * time.Millisecond
+)
+
+type pooledZkpClient struct {
+	client *ethclient.Client
+}
+
+type zkpClientPool struct {
+	mu      sync.Mutex
+	clients map[int64]*pooledZkpClient
+}
+
+func newZkpClientPool() *zkpClientPool {
+	return &zkpClientPool{clients: make(map[int64]*pooledZkpClient)}
+}
+
+// Get returns a dialed, health-checked client for chain, dialing and caching
+// it on first use. Dialing is retried a few times to absorb transient RPC
+// hiccups.
+func (p *zkpClientPool) Get(chain *ChainConfig) (*ethclient.Client, error) {
+	p.mu.Lock()
+	pooled, ok := p.clients[chain.ChainId]
+	p.mu.Unlock()
+
+	if ok {
+		if err := p.healthCheck(pooled.client, chain); err == nil {
+			return pooled.client, nil
+		}
+		// Stale or unhealthy connection, fall through and redial.
+		p.mu.Lock()
+		delete(p.clients, chain.ChainId)
+		p.mu.Unlock()
+	}
+
+	client, err := p.dialWithRetry(chain)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.clients[chain.ChainId] = &pooledZkpClient{client: client}
+	p.mu.Unlock()
+
+	return client, nil
+}
+
+func (p *zkpClientPool) dialWithRetry(chain *ChainConfig) (*ethclient.Client, error) {
+	var lastErr error
+	for attempt := 1; attempt <= zkpDialMaxAttempts; attempt++ {
+		client, err := ethclient.Dial(chain.RpcUrl)
+		if err == nil {
+			if err = p.healthCheck(client, chain); err == nil {
+				return client, nil
+			}
+			client.Close()
+		}
+		lastErr = err
+		common.SysLog(fmt.Sprintf("zkp: dial chain %d (%s) attempt %d/%d failed: %v", chain.ChainId, chain.Name, attempt, zkpDialMaxAttempts, lastErr))
+		if attempt < zkpDialMaxAttempts {
+			time.Sleep(zkpDialRetryDelay * time.Duration(attempt))
+		}
+	}
+	return nil, fmt.Errorf("failed to connect to ethereum client for chain %d: %w", chain.ChainId, lastErr)
+}
+
+func (p *zkpClientPool) healthCheck(client *ethclient.Client, chain *ChainConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("health check failed for chain %d: %w", chain.ChainId, err)
+	}
+	if id.Cmp(big.NewInt(chain.ChainId)) != 0 {
+		return fmt.Errorf("rpc %s reports chain id %s, expected %d", chain.RpcUrl, id.String(), chain.ChainId)
+	}
+	return nil
+}
+
+// prune closes and drops any pooled clients for chains that are no longer
+// present in the live configuration, e.g. after ReloadZkpChains.
+func (p *zkpClientPool) prune(live map[int64]*ChainConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for chainId, pooled := range p.clients {
+		if _, ok := live[chainId]; !ok {
+			pooled.client.Close()
+			delete(p.clients, chainId)
+		}
+	}
+}
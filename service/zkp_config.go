@@ -0,0 +1,156 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/QuantumNous/new-api/common"
+	"github.com/QuantumNous/new-api/model"
+)
+
+// ChainConfig describes a single EVM chain the ZKP login flow is allowed to
+// verify proofs against. Multiple chains can be registered at once so that
+// `MembershipStatus.IsCrossChain` can be resolved by actually querying every
+// configured chain instead of a single hardcoded one.
+type ChainConfig struct {
+	ChainId                int64  `json:"chain_id"`
+	Name                   string `json:"name"`
+	RpcUrl                 string `json:"rpc_url"`
+	ZkpContractAddress     string `json:"zkp_contract_address"`
+	MembershipQueryAddress string `json:"membership_query_address"`
+	AllowedClubName        string `json:"allowed_club_name"`
+}
+
+// zkpChainRegistry holds the set of chains the ZKP login flow knows about.
+// It is safe for concurrent use and can be swapped out wholesale at runtime
+// (see ReloadZkpChains) without restarting the process.
+type zkpChainRegistry struct {
+	mu      sync.RWMutex
+	chains  map[int64]*ChainConfig
+	primary int64
+}
+
+// defaultZkpChainRegistry seeds the registry with the values that used to be
+// hardcoded constants, so existing deployments keep working until an admin
+// registers additional chains.
+var defaultZkpChainRegistry = &zkpChainRegistry{
+	chains: map[int64]*ChainConfig{
+		ZkpChainId: {
+			ChainId:                ZkpChainId,
+			Name:                   "base-mainnet",
+			RpcUrl:                 ZkpRpcUrl,
+			ZkpContractAddress:     ZkpContractAddress,
+			MembershipQueryAddress: MembershipQueryAddress,
+			AllowedClubName:        AllowedClubName,
+		},
+	},
+	primary: ZkpChainId,
+}
+
+// GetZkpChains returns every currently registered chain.
+func GetZkpChains() []*ChainConfig {
+	defaultZkpChainRegistry.mu.RLock()
+	defer defaultZkpChainRegistry.mu.RUnlock()
+
+	chains := make([]*ChainConfig, 0, len(defaultZkpChainRegistry.chains))
+	for _, chain := range defaultZkpChainRegistry.chains {
+		chains = append(chains, chain)
+	}
+	return chains
+}
+
+// GetZkpChain returns the chain registered under chainId, if any.
+func GetZkpChain(chainId int64) (*ChainConfig, bool) {
+	defaultZkpChainRegistry.mu.RLock()
+	defer defaultZkpChainRegistry.mu.RUnlock()
+
+	chain, ok := defaultZkpChainRegistry.chains[chainId]
+	return chain, ok
+}
+
+// GetPrimaryZkpChain returns the chain used when a login request does not
+// specify which chain to verify against.
+func GetPrimaryZkpChain() (*ChainConfig, bool) {
+	defaultZkpChainRegistry.mu.RLock()
+	defer defaultZkpChainRegistry.mu.RUnlock()
+
+	chain, ok := defaultZkpChainRegistry.chains[defaultZkpChainRegistry.primary]
+	return chain, ok
+}
+
+// ReloadZkpChains replaces the registered chain set and primary chain. It is
+// intended to be called from the admin settings API so chains can be added,
+// removed or repointed without a restart. The new set is persisted via
+// model.SaveZkpChainRegistry so a later restart picks it back up instead of
+// silently reverting to the hardcoded default (see LoadPersistedZkpChains).
+// Any ethclient connections that are no longer referenced by the new set are
+// dropped from the client pool.
+func ReloadZkpChains(chains []*ChainConfig, primary int64) error {
+	if len(chains) == 0 {
+		return fmt.Errorf("zkp: at least one chain must be configured")
+	}
+
+	next := make(map[int64]*ChainConfig, len(chains))
+	for _, chain := range chains {
+		if chain.ChainId == 0 {
+			return fmt.Errorf("zkp: chain %q is missing a chain_id", chain.Name)
+		}
+		next[chain.ChainId] = chain
+	}
+	if _, ok := next[primary]; !ok {
+		return fmt.Errorf("zkp: primary chain_id %d is not in the configured chain set", primary)
+	}
+
+	chainsJSON, err := json.Marshal(chains)
+	if err != nil {
+		return fmt.Errorf("zkp: failed to serialize chain set: %w", err)
+	}
+	if err := model.SaveZkpChainRegistry(string(chainsJSON), primary); err != nil {
+		return fmt.Errorf("zkp: failed to persist chain set: %w", err)
+	}
+
+	defaultZkpChainRegistry.mu.Lock()
+	defaultZkpChainRegistry.chains = next
+	defaultZkpChainRegistry.primary = primary
+	defaultZkpChainRegistry.mu.Unlock()
+
+	ethClientPool.prune(next)
+	return nil
+}
+
+// LoadPersistedZkpChains restores a previously admin-registered chain set
+// from storage, overriding the hardcoded default. It must be called once
+// during startup after the database connection is established (a package
+// init() would run too early for that). If nothing was ever
+// persisted, the hardcoded default is left in place.
+func LoadPersistedZkpChains() error {
+	chainsJSON, primary, ok, err := model.LoadZkpChainRegistry()
+	if err != nil {
+		return fmt.Errorf("zkp: failed to load persisted chain set: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	var chains []*ChainConfig
+	if err := json.Unmarshal([]byte(chainsJSON), &chains); err != nil {
+		return fmt.Errorf("zkp: failed to parse persisted chain set: %w", err)
+	}
+
+	next := make(map[int64]*ChainConfig, len(chains))
+	for _, chain := range chains {
+		next[chain.ChainId] = chain
+	}
+	if _, ok := next[primary]; !ok {
+		common.SysLog(fmt.Sprintf("zkp: persisted primary chain_id %d missing from persisted chain set, keeping default config", primary))
+		return nil
+	}
+
+	defaultZkpChainRegistry.mu.Lock()
+	defaultZkpChainRegistry.chains = next
+	defaultZkpChainRegistry.primary = primary
+	defaultZkpChainRegistry.mu.Unlock()
+
+	return nil
+}
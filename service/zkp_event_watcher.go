@@ -0,0 +1,390 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/QuantumNous/new-api/common"
+	"github.com/QuantumNous/new-api/model"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	zkpHashCacheCapacity = 50_000
+	zkpPollInterval      = 15 * time.Second
+	zkpResubscribeDelay  = 5 * time.Second
+)
+
+// ZkpEventWatcher streams HashRevoked/HashActivated events for one chain's
+// verifier contract and keeps an in-memory cache (backed by the persisted
+// zkp_hash_status table) so IsZkpValid no longer needs an RPC round-trip on
+// every authenticated request.
+type ZkpEventWatcher struct {
+	chain *ChainConfig
+	cache *zkpHashCache
+
+	mu             sync.RWMutex
+	lastBlock      uint64
+	mode           string
+	reconnectCount int
+}
+
+// WatcherHealth is the admin-facing snapshot of a watcher's state.
+type WatcherHealth struct {
+	ChainId        int64  `json:"chain_id"`
+	ChainName      string `json:"chain_name"`
+	Mode           string `json:"mode"`
+	LastBlock      uint64 `json:"last_block"`
+	ReconnectCount int    `json:"reconnect_count"`
+}
+
+var zkpWatchers = struct {
+	mu      sync.Mutex
+	byChain map[int64]*ZkpEventWatcher
+}{byChain: make(map[int64]*ZkpEventWatcher)}
+
+func getZkpEventWatcher(chainId int64) *ZkpEventWatcher {
+	zkpWatchers.mu.Lock()
+	defer zkpWatchers.mu.Unlock()
+	return zkpWatchers.byChain[chainId]
+}
+
+// StartZkpEventWatchers launches one watcher per configured chain. Each
+// watcher backfills from the last block it processed (or the current head on
+// first run) and then keeps streaming new events until ctx is cancelled.
+func StartZkpEventWatchers(ctx context.Context) {
+	for _, chain := range GetZkpChains() {
+		watcher := newZkpEventWatcher(chain)
+
+		zkpWatchers.mu.Lock()
+		zkpWatchers.byChain[chain.ChainId] = watcher
+		zkpWatchers.mu.Unlock()
+
+		go watcher.run(ctx)
+	}
+}
+
+// GetZkpWatcherHealth reports last processed block and reconnect counts for
+// every running watcher, for the admin health endpoint.
+func GetZkpWatcherHealth() []WatcherHealth {
+	zkpWatchers.mu.Lock()
+	watchers := make([]*ZkpEventWatcher, 0, len(zkpWatchers.byChain))
+	for _, w := range zkpWatchers.byChain {
+		watchers = append(watchers, w)
+	}
+	zkpWatchers.mu.Unlock()
+
+	health := make([]WatcherHealth, 0, len(watchers))
+	for _, w := range watchers {
+		w.mu.RLock()
+		health = append(health, WatcherHealth{
+			ChainId:        w.chain.ChainId,
+			ChainName:      w.chain.Name,
+			Mode:           w.mode,
+			LastBlock:      w.lastBlock,
+			ReconnectCount: w.reconnectCount,
+		})
+		w.mu.RUnlock()
+	}
+	return health
+}
+
+func newZkpEventWatcher(chain *ChainConfig) *ZkpEventWatcher {
+	return &ZkpEventWatcher{
+		chain: chain,
+		cache: newZkpHashCache(zkpHashCacheCapacity),
+	}
+}
+
+// Lookup returns the cached status for zkpHash, if the watcher has seen it.
+func (w *ZkpEventWatcher) Lookup(zkpHash string) (ZkpStatus, bool) {
+	return w.cache.Get(zkpHash)
+}
+
+func (w *ZkpEventWatcher) run(ctx context.Context) {
+	w.primeFromStorage()
+
+	client, err := ethClientPool.Get(w.chain)
+	if err != nil {
+		common.SysLog(fmt.Sprintf("zkp watcher: chain %d: %v", w.chain.ChainId, err))
+		return
+	}
+
+	zkpABI, _, err := getABIs()
+	if err != nil {
+		common.SysLog(fmt.Sprintf("zkp watcher: chain %d: %v", w.chain.ChainId, err))
+		return
+	}
+
+	if err := w.backfill(ctx, client, zkpABI); err != nil {
+		common.SysLog(fmt.Sprintf("zkp watcher: chain %d backfill failed: %v", w.chain.ChainId, err))
+	}
+
+	for {
+		err := w.subscribe(ctx, client, zkpABI)
+		if ctx.Err() != nil {
+			return
+		}
+		common.SysLog(fmt.Sprintf("zkp watcher: chain %d subscription ended (%v), falling back to polling", w.chain.ChainId, err))
+		w.setMode("poll")
+		w.poll(ctx, client, zkpABI)
+		if ctx.Err() != nil {
+			return
+		}
+
+		w.mu.Lock()
+		w.reconnectCount++
+		w.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(zkpResubscribeDelay):
+		}
+	}
+}
+
+// primeFromStorage loads whatever was persisted from a previous run so the
+// cache is warm (and lastBlock known) before the first live query.
+func (w *ZkpEventWatcher) primeFromStorage() {
+	lastBlock, err := model.GetZkpLastProcessedBlock(w.chain.ChainId)
+	if err != nil {
+		common.SysLog(fmt.Sprintf("zkp watcher: chain %d: failed to load last processed block: %v", w.chain.ChainId, err))
+	} else {
+		w.setLastBlock(lastBlock)
+	}
+
+	statuses, err := model.ListZkpHashStatuses(w.chain.ChainId)
+	if err != nil {
+		common.SysLog(fmt.Sprintf("zkp watcher: chain %d: failed to load persisted hash statuses: %v", w.chain.ChainId, err))
+		return
+	}
+	for hash, status := range statuses {
+		w.cache.Put(hash, ZkpStatus{IsActive: status.IsActive, Deployer: status.Deployer, Exists: true})
+	}
+}
+
+// backfill catches up on any HashRevoked/HashActivated events emitted between
+// the last processed block and the current head, e.g. after a restart.
+func (w *ZkpEventWatcher) backfill(ctx context.Context, client *ethclient.Client, zkpABI abi.ABI) error {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get head block: %w", err)
+	}
+
+	from := w.getLastBlock()
+	if from == 0 {
+		from = head // Nothing persisted: start tracking from the current head.
+	}
+	if from >= head {
+		w.setLastBlock(head)
+		return nil
+	}
+
+	query := w.filterQuery(zkpABI, new(big.Int).SetUint64(from+1), new(big.Int).SetUint64(head))
+	logs, err := client.FilterLogs(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to backfill logs: %w", err)
+	}
+
+	for _, logEntry := range logs {
+		w.applyLog(logEntry, zkpABI)
+	}
+	w.setLastBlock(head)
+	return nil
+}
+
+// subscribe streams new events over a live subscription (e.g. a websocket
+// endpoint). It returns when the subscription errors out or ctx is done, at
+// which point the caller falls back to polling.
+func (w *ZkpEventWatcher) subscribe(ctx context.Context, client *ethclient.Client, zkpABI abi.ABI) error {
+	query := w.filterQuery(zkpABI, nil, nil)
+	logsCh := make(chan types.Log)
+
+	sub, err := client.SubscribeFilterLogs(ctx, query, logsCh)
+	if err != nil {
+		return fmt.Errorf("subscribe not supported: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	w.setMode("subscribe")
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case logEntry := <-logsCh:
+			w.applyLog(logEntry, zkpABI)
+			w.setLastBlock(logEntry.BlockNumber)
+		}
+	}
+}
+
+// poll re-queries FilterLogs on an interval for RPC endpoints (plain HTTP)
+// that don't support eth_subscribe.
+func (w *ZkpEventWatcher) poll(ctx context.Context, client *ethclient.Client, zkpABI abi.ABI) {
+	ticker := time.NewTicker(zkpPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			head, err := client.BlockNumber(ctx)
+			if err != nil {
+				common.SysLog(fmt.Sprintf("zkp watcher: chain %d: poll failed to get head: %v", w.chain.ChainId, err))
+				continue
+			}
+			from := w.getLastBlock() + 1
+			if from > head {
+				continue
+			}
+			query := w.filterQuery(zkpABI, new(big.Int).SetUint64(from), new(big.Int).SetUint64(head))
+			logs, err := client.FilterLogs(ctx, query)
+			if err != nil {
+				common.SysLog(fmt.Sprintf("zkp watcher: chain %d: poll failed: %v", w.chain.ChainId, err))
+				continue
+			}
+			for _, logEntry := range logs {
+				w.applyLog(logEntry, zkpABI)
+			}
+			w.setLastBlock(head)
+		}
+	}
+}
+
+func (w *ZkpEventWatcher) filterQuery(zkpABI abi.ABI, fromBlock, toBlock *big.Int) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []ethcommon.Address{ethcommon.HexToAddress(w.chain.ZkpContractAddress)},
+		Topics: [][]ethcommon.Hash{{
+			zkpABI.Events["HashRevoked"].ID,
+			zkpABI.Events["HashActivated"].ID,
+		}},
+	}
+}
+
+func (w *ZkpEventWatcher) applyLog(logEntry types.Log, zkpABI abi.ABI) {
+	if len(logEntry.Topics) == 0 {
+		return
+	}
+
+	event, err := zkpABI.EventByID(logEntry.Topics[0])
+	if err != nil {
+		return
+	}
+
+	values, err := zkpABI.Unpack(event.Name, logEntry.Data)
+	if err != nil || len(values) != 2 {
+		common.SysLog(fmt.Sprintf("zkp watcher: chain %d: failed to decode %s: %v", w.chain.ChainId, event.Name, err))
+		return
+	}
+
+	hashBytes, ok := values[0].([32]byte)
+	if !ok {
+		return
+	}
+	deployer, ok := values[1].(ethcommon.Address)
+	if !ok {
+		return
+	}
+
+	hash := new(big.Int).SetBytes(hashBytes[:]).String()
+	isActive := event.Name == "HashActivated"
+
+	status := ZkpStatus{IsActive: isActive, Deployer: deployer.Hex(), Exists: true}
+	w.cache.Put(hash, status)
+
+	if err := model.UpsertZkpHashStatus(w.chain.ChainId, hash, status.IsActive, status.Deployer, logEntry.BlockNumber); err != nil {
+		common.SysLog(fmt.Sprintf("zkp watcher: chain %d: failed to persist hash status: %v", w.chain.ChainId, err))
+	}
+}
+
+func (w *ZkpEventWatcher) getLastBlock() uint64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.lastBlock
+}
+
+func (w *ZkpEventWatcher) setLastBlock(block uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if block > w.lastBlock {
+		w.lastBlock = block
+	}
+	if err := model.SetZkpLastProcessedBlock(w.chain.ChainId, w.lastBlock); err != nil {
+		common.SysLog(fmt.Sprintf("zkp watcher: chain %d: failed to persist last block: %v", w.chain.ChainId, err))
+	}
+}
+
+func (w *ZkpEventWatcher) setMode(mode string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.mode = mode
+}
+
+// zkpHashCache is a small capacity-bounded LRU of zkpHash -> ZkpStatus.
+type zkpHashCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type zkpHashCacheEntry struct {
+	hash   string
+	status ZkpStatus
+}
+
+func newZkpHashCache(capacity int) *zkpHashCache {
+	return &zkpHashCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *zkpHashCache) Get(hash string) (ZkpStatus, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[hash]
+	if !ok {
+		return ZkpStatus{}, false
+	}
+	c.ll.MoveToFront(elem)
+	return elem.Value.(*zkpHashCacheEntry).status, true
+}
+
+func (c *zkpHashCache) Put(hash string, status ZkpStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[hash]; ok {
+		elem.Value.(*zkpHashCacheEntry).status = status
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	elem := c.ll.PushFront(&zkpHashCacheEntry{hash: hash, status: status})
+	c.items[hash] = elem
+
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*zkpHashCacheEntry).hash)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// nonceKey identifies one (chain, signer address) pair. The same signer
+// address can submit to several configured chains, and each chain tracks its
+// own account nonce independently on its own ledger, so a single counter per
+// address would desync the moment a second chain is in play.
+type nonceKey struct {
+	chainId int64
+	address ethcommon.Address
+}
+
+// NonceManager serializes nonce allocation per (chain, address) so concurrent
+// VerifyProof calls against the same signer don't race and produce
+// "nonce too low" / "replacement underpriced" errors under load.
+type NonceManager struct {
+	mu    sync.Mutex
+	next  map[nonceKey]uint64
+	ready map[nonceKey]bool
+}
+
+// zkpNonceManager is shared by every VerifyProof call.
+var zkpNonceManager = &NonceManager{
+	next:  make(map[nonceKey]uint64),
+	ready: make(map[nonceKey]bool),
+}
+
+// Next returns the next nonce to use for from on chainId, seeding it from
+// PendingNonceAt the first time this (chainId, from) pair is seen and
+// incrementing a local counter thereafter.
+func (m *NonceManager) Next(ctx context.Context, client *ethclient.Client, chainId int64, from ethcommon.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := nonceKey{chainId: chainId, address: from}
+
+	if !m.ready[key] {
+		pending, err := client.PendingNonceAt(ctx, from)
+		if err != nil {
+			return 0, fmt.Errorf("failed to seed nonce: %w", err)
+		}
+		m.next[key] = pending
+		m.ready[key] = true
+	}
+
+	nonce := m.next[key]
+	m.next[key] = nonce + 1
+	return nonce, nil
+}
+
+// Resync forces the next Next() call for (chainId, from) to reseed from
+// PendingNonceAt, e.g. after a transaction failed to broadcast and the
+// locally tracked nonce may have drifted from the chain's.
+func (m *NonceManager) Resync(chainId int64, from ethcommon.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.ready, nonceKey{chainId: chainId, address: from})
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+func TestNonceManagerPerChainIsolation(t *testing.T) {
+	addr := ethcommon.HexToAddress("0x0000000000000000000000000000000000000001")
+	m := &NonceManager{
+		next:  map[nonceKey]uint64{{chainId: 1, address: addr}: 5, {chainId: 2, address: addr}: 100},
+		ready: map[nonceKey]bool{{chainId: 1, address: addr}: true, {chainId: 2, address: addr}: true},
+	}
+
+	n1, err := m.Next(context.Background(), nil, 1, addr)
+	if err != nil || n1 != 5 {
+		t.Fatalf("chain 1: got (%d, %v), want (5, nil)", n1, err)
+	}
+	n2, err := m.Next(context.Background(), nil, 2, addr)
+	if err != nil || n2 != 100 {
+		t.Fatalf("chain 2: got (%d, %v), want (100, nil)", n2, err)
+	}
+
+	// Chain 1's counter must advance independently of chain 2's.
+	n1b, err := m.Next(context.Background(), nil, 1, addr)
+	if err != nil || n1b != 6 {
+		t.Fatalf("chain 1 second call: got (%d, %v), want (6, nil)", n1b, err)
+	}
+}
+
+func TestNonceManagerResyncIsPerChain(t *testing.T) {
+	addr := ethcommon.HexToAddress("0x0000000000000000000000000000000000000002")
+	m := &NonceManager{
+		next:  map[nonceKey]uint64{{chainId: 1, address: addr}: 5, {chainId: 2, address: addr}: 10},
+		ready: map[nonceKey]bool{{chainId: 1, address: addr}: true, {chainId: 2, address: addr}: true},
+	}
+
+	m.Resync(1, addr)
+
+	if m.ready[nonceKey{chainId: 1, address: addr}] {
+		t.Fatal("Resync(1, addr) should have cleared chain 1's ready flag")
+	}
+	if !m.ready[nonceKey{chainId: 2, address: addr}] {
+		t.Fatal("Resync(1, addr) should not affect chain 2's ready flag")
+	}
+}
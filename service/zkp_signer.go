@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/QuantumNous/new-api/common"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer backend names for common.ZkpSignerBackend (ZKP_SIGNER_BACKEND).
+const (
+	ZkpSignerRawKey   = "raw_key"
+	ZkpSignerKeystore = "keystore"
+	ZkpSignerClef     = "clef"
+)
+
+// ZkpSigner produces the signing account and transaction options VerifyProof
+// submits with, abstracting over where the private key actually lives.
+type ZkpSigner interface {
+	// Address returns the account this signer transacts as.
+	Address() (ethcommon.Address, error)
+	// TransactOpts returns transaction signing options bound to chainID.
+	TransactOpts(chainID *big.Int) (*bind.TransactOpts, error)
+}
+
+// zkpSignerOnce builds the configured signer backend exactly once and hands
+// every caller the same instance, so a keystore/clef-backed signer is
+// unlocked a single time at first use instead of once per VerifyProof call.
+var (
+	zkpSignerOnce sync.Once
+	zkpSignerInst ZkpSigner
+	zkpSignerErr  error
+)
+
+// GetZkpSigner returns the configured signer backend. Raw hex key is kept as
+// the default for backwards compatibility; keystore and clef let operators
+// keep the private key encrypted at rest, or entirely out of this process.
+func GetZkpSigner() (ZkpSigner, error) {
+	zkpSignerOnce.Do(func() {
+		switch common.ZkpSignerBackend {
+		case "", ZkpSignerRawKey:
+			zkpSignerInst = &rawKeySigner{hexKey: common.ZkpPrivateKey}
+		case ZkpSignerKeystore:
+			zkpSignerInst = &keystoreSigner{path: common.ZkpKeystorePath, passphrase: common.ZkpKeystorePassphrase}
+		case ZkpSignerClef:
+			zkpSignerInst = &clefSigner{endpoint: common.ZkpClefEndpoint, account: common.ZkpClefAccountAddress}
+		default:
+			zkpSignerErr = fmt.Errorf("zkp: unknown signer backend %q", common.ZkpSignerBackend)
+		}
+	})
+	return zkpSignerInst, zkpSignerErr
+}
+
+// rawKeySigner is the original behavior: an unencrypted hex private key read
+// from configuration.
+type rawKeySigner struct {
+	hexKey string
+}
+
+func (s *rawKeySigner) privateKey() (*ecdsa.PrivateKey, error) {
+	if s.hexKey == "" {
+		return nil, errors.New("ZKP_PRIVATE_KEY not configured")
+	}
+	return crypto.HexToECDSA(strings.TrimPrefix(s.hexKey, "0x"))
+}
+
+func (s *rawKeySigner) Address() (ethcommon.Address, error) {
+	key, err := s.privateKey()
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	return crypto.PubkeyToAddress(key.PublicKey), nil
+}
+
+func (s *rawKeySigner) TransactOpts(chainID *big.Int) (*bind.TransactOpts, error) {
+	key, err := s.privateKey()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewKeyedTransactorWithChainID(key, chainID)
+}
+
+// keystoreSigner unlocks a go-ethereum keystore JSON file with a passphrase
+// once (scrypt is deliberately slow) and caches the decrypted key, so the raw
+// key never has to sit unencrypted in env/DB and repeated logins don't pay
+// the KDF cost again.
+type keystoreSigner struct {
+	path       string
+	passphrase string
+
+	once sync.Once
+	key  *keystore.Key
+	err  error
+}
+
+func (s *keystoreSigner) decrypt() (*keystore.Key, error) {
+	s.once.Do(func() {
+		if s.path == "" {
+			s.err = errors.New("zkp: keystore path not configured")
+			return
+		}
+		raw, err := os.ReadFile(s.path)
+		if err != nil {
+			s.err = fmt.Errorf("failed to read keystore file: %w", err)
+			return
+		}
+		s.key, s.err = keystore.DecryptKey(raw, s.passphrase)
+		if s.err != nil {
+			s.err = fmt.Errorf("failed to decrypt keystore file: %w", s.err)
+		}
+	})
+	return s.key, s.err
+}
+
+func (s *keystoreSigner) Address() (ethcommon.Address, error) {
+	key, err := s.decrypt()
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	return key.Address, nil
+}
+
+func (s *keystoreSigner) TransactOpts(chainID *big.Int) (*bind.TransactOpts, error) {
+	key, err := s.decrypt()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+}
+
+// clefSigner delegates signing to a Clef (or other go-ethereum external
+// signer) JSON-RPC process, so the private key never leaves the HSM/Clef and
+// can be rotated without redeploying.
+type clefSigner struct {
+	endpoint string
+	account  string
+}
+
+func (s *clefSigner) dial() (*external.ExternalSigner, accounts.Account, error) {
+	if s.endpoint == "" {
+		return nil, accounts.Account{}, errors.New("zkp: clef endpoint not configured")
+	}
+	if s.account == "" {
+		return nil, accounts.Account{}, errors.New("zkp: clef account address not configured")
+	}
+	signer, err := external.NewExternalSigner(s.endpoint)
+	if err != nil {
+		return nil, accounts.Account{}, fmt.Errorf("failed to connect to clef at %s: %w", s.endpoint, err)
+	}
+	return signer, accounts.Account{Address: ethcommon.HexToAddress(s.account)}, nil
+}
+
+func (s *clefSigner) Address() (ethcommon.Address, error) {
+	_, account, err := s.dial()
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	return account.Address, nil
+}
+
+func (s *clefSigner) TransactOpts(chainID *big.Int) (*bind.TransactOpts, error) {
+	signer, account, err := s.dial()
+	if err != nil {
+		return nil, err
+	}
+	opts := bind.NewClefTransactor(signer, account)
+	opts.Context = context.Background()
+	return opts, nil
+}
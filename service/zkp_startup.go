@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/QuantumNous/new-api/model"
+)
+
+// StartZkp wires up everything the zkp login feature needs once the database
+// connection is established: migrating its tables, restoring any
+// admin-persisted chain registry, and starting the per-chain event watchers.
+// It must be called from the application's startup path (after
+// model.InitDB, before the server starts accepting requests) the same way
+// every other feature's startup hook is.
+func StartZkp(ctx context.Context) error {
+	if err := model.MigrateZkpTables(); err != nil {
+		return fmt.Errorf("zkp: failed to migrate tables: %w", err)
+	}
+	if err := LoadPersistedZkpChains(); err != nil {
+		return fmt.Errorf("zkp: failed to load persisted chain registry: %w", err)
+	}
+	// Without a running watcher, IsZkpValid's cache lookup always misses and
+	// every check falls through to a live getHashStatus RPC call.
+	StartZkpEventWatchers(ctx)
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/QuantumNous/new-api/common"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// zkpTxWaitPerAttempt bounds how long we wait for a submission to be
+	// mined before concluding it's stuck and bumping fees.
+	zkpTxWaitPerAttempt = 20 * time.Second
+	// zkpTxMaxBumps is how many times a stuck transaction gets its fee cap
+	// bumped and resubmitted before VerifyProof gives up.
+	zkpTxMaxBumps = 3
+	// zkpFeeBumpNumerator/zkpFeeBumpDenominator bump GasFeeCap/GasTipCap by
+	// 12.5% per retry, matching go-ethereum's own replacement-tx minimum.
+	zkpFeeBumpNumerator   = 1125
+	zkpFeeBumpDenominator = 1000
+)
+
+// sendAndWaitMined submits method(params...) via boundContract, waiting up
+// to zkpTxWaitPerAttempt for it to be mined. If it's still pending after
+// that, GasFeeCap/GasTipCap are bumped by 12.5% and the same nonce is
+// resubmitted, up to zkpTxMaxBumps times.
+func sendAndWaitMined(ctx context.Context, client *ethclient.Client, boundContract *bind.BoundContract, auth *bind.TransactOpts, method string, params ...interface{}) (*types.Receipt, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= zkpTxMaxBumps; attempt++ {
+		tx, err := boundContract.Transact(auth, method, params...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send transaction: %w", err)
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, zkpTxWaitPerAttempt)
+		receipt, err := bind.WaitMined(waitCtx, client, tx)
+		cancel()
+		if err == nil {
+			return receipt, nil
+		}
+		lastErr = fmt.Errorf("tx %s not mined: %w", tx.Hash().Hex(), err)
+
+		if attempt == zkpTxMaxBumps {
+			break
+		}
+
+		common.SysLog(fmt.Sprintf("zkp: %s, bumping fees and resubmitting (attempt %d/%d)", lastErr, attempt+1, zkpTxMaxBumps))
+		auth.GasFeeCap = bumpFee(auth.GasFeeCap)
+		auth.GasTipCap = bumpFee(auth.GasTipCap)
+	}
+
+	return nil, lastErr
+}
+
+func bumpFee(fee *big.Int) *big.Int {
+	if fee == nil {
+		return nil
+	}
+	return new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(zkpFeeBumpNumerator)), big.NewInt(zkpFeeBumpDenominator))
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBumpFee(t *testing.T) {
+	got := bumpFee(big.NewInt(1000))
+	want := big.NewInt(1125)
+	if got.Cmp(want) != 0 {
+		t.Fatalf("bumpFee(1000) = %s, want %s", got, want)
+	}
+}
+
+func TestBumpFeeNil(t *testing.T) {
+	if bumpFee(nil) != nil {
+		t.Fatal("bumpFee(nil) should return nil")
+	}
+}